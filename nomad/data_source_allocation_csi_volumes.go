@@ -0,0 +1,141 @@
+package nomad
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func dataSourceAllocationCSIVolumes() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAllocationCSIVolumesRead,
+
+		Schema: map[string]*schema.Schema{
+			"allocation_id": {
+				Description: "Allocation ID",
+				Required:    true,
+				Type:        schema.TypeString,
+			},
+
+			"volumes": {
+				Description: "CSI volumes requested by the allocation's task group.",
+				Computed:    true,
+				Type:        schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"volume_id": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"read_only": {
+							Computed: true,
+							Type:     schema.TypeBool,
+						},
+						"mounts": {
+							Computed: true,
+							Type:     schema.TypeList,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"task": {
+										Computed: true,
+										Type:     schema.TypeString,
+									},
+									"destination": {
+										Computed: true,
+										Type:     schema.TypeString,
+									},
+									"read_only": {
+										Computed: true,
+										Type:     schema.TypeBool,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAllocationCSIVolumesRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+
+	id := d.Get("allocation_id").(string)
+	log.Printf("[DEBUG] Getting allocation %q", id)
+	alloc, _, err := client.Allocations().Info(id, nil)
+	if err != nil {
+		// As of Nomad 0.4.1, the API client returns an error for 404
+		// rather than a nil result, so we must check this way.
+		if strings.Contains(err.Error(), "404") {
+			return err
+		}
+
+		return fmt.Errorf("error checking for allocation: %#v", err)
+	}
+	if alloc.Job == nil {
+		return fmt.Errorf("allocation %q has no job", id)
+	}
+
+	log.Printf("[DEBUG] Read CSI volumes for allocation %q", id)
+
+	d.SetId(id)
+
+	return d.Set("volumes", allocationCSIVolumesRaw(alloc))
+}
+
+// allocationCSIVolumesRaw returns the CSI volumes requested by the
+// allocation's task group, sorted by name, along with the tasks mounting them.
+func allocationCSIVolumesRaw(alloc *api.Allocation) []interface{} {
+	volumes := make([]interface{}, 0)
+	for _, tg := range alloc.Job.TaskGroups {
+		if tg.Name == nil || *tg.Name != alloc.TaskGroup {
+			continue
+		}
+
+		for name, v := range tg.Volumes {
+			if v.Type != "csi" {
+				continue
+			}
+
+			mounts := make([]interface{}, 0)
+			for _, task := range tg.Tasks {
+				for _, vm := range task.VolumeMounts {
+					if vm.Volume == nil || *vm.Volume != name {
+						continue
+					}
+
+					mountM := make(map[string]interface{})
+					mountM["task"] = task.Name
+					mountM["destination"] = ""
+					if vm.Destination != nil {
+						mountM["destination"] = *vm.Destination
+					}
+					mountM["read_only"] = vm.ReadOnly != nil && *vm.ReadOnly
+					mounts = append(mounts, mountM)
+				}
+			}
+
+			volumes = append(volumes, map[string]interface{}{
+				"name":      name,
+				"volume_id": v.Source,
+				"read_only": v.ReadOnly,
+				"mounts":    mounts,
+			})
+		}
+	}
+	sort.Slice(volumes, func(i, j int) bool {
+		return volumes[i].(map[string]interface{})["name"].(string) <
+			volumes[j].(map[string]interface{})["name"].(string)
+	})
+
+	return volumes
+}
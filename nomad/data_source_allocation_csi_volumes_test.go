@@ -0,0 +1,164 @@
+package nomad
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/stretchr/testify/require"
+
+	"github.com/terraform-providers/terraform-provider-nomad/nomad/core/helper"
+)
+
+func TestDataSourceAllocationCSIVolumes(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t); testCheckMinVersion(t, "0.11.0-beta1") },
+		Steps: []resource.TestStep{
+			{
+				Config: testDataSourceAllocationCSIVolumes_config,
+				Check:  testDataSourceAllocationCSIVolumes_check,
+			},
+		},
+
+		CheckDestroy: testResourceJob_checkDestroy("foo-csi-volumes"),
+	})
+}
+
+// The test cluster isn't guaranteed to run any CSI plugins, so the job
+// doesn't request any volumes.
+var testDataSourceAllocationCSIVolumes_config = `
+resource "nomad_job" "test" {
+	detach = false
+	jobspec = <<EOT
+		job "foo-csi-volumes" {
+			datacenters = ["dc1"]
+			type = "batch"
+			group "foo" {
+				task "foo" {
+					driver = "raw_exec"
+					config {
+						command = "/bin/sleep"
+						args = ["1"]
+					}
+					resources {
+						cpu = 100
+						memory = 10
+					}
+				}
+			}
+		}
+	EOT
+}
+
+data "nomad_allocation_csi_volumes" "test" {
+	allocation_id = nomad_job.test.allocation_ids[0]
+}
+`
+
+func testDataSourceAllocationCSIVolumes_check(s *terraform.State) error {
+	resourceState := s.Modules[0].Resources["data.nomad_allocation_csi_volumes.test"]
+	if resourceState == nil {
+		return fmt.Errorf("resource not found in state %v", s.Modules[0].Resources)
+	}
+
+	iState := resourceState.Primary
+	if iState == nil {
+		return fmt.Errorf("resource has no primary instance")
+	}
+
+	if got := iState.Attributes["volumes.#"]; got != "0" {
+		return fmt.Errorf("expected no CSI volumes, got %s", got)
+	}
+
+	return nil
+}
+
+func TestAllocationCSIVolumesRaw(t *testing.T) {
+	require := require.New(t)
+
+	alloc := &api.Allocation{
+		TaskGroup: "web",
+		Job: &api.Job{
+			TaskGroups: []*api.TaskGroup{
+				{
+					Name: helper.StringToPtr("other"),
+					Volumes: map[string]*api.VolumeRequest{
+						"ignored": {Name: "ignored", Type: "csi", Source: "ignored-vol"},
+					},
+				},
+				{
+					Name: helper.StringToPtr("web"),
+					Volumes: map[string]*api.VolumeRequest{
+						"logs":  {Name: "logs", Type: "csi", Source: "logs-vol"},
+						"data":  {Name: "data", Type: "csi", Source: "data-vol", ReadOnly: true},
+						"certs": {Name: "certs", Type: "host", Source: "certs"},
+					},
+					Tasks: []*api.Task{
+						{
+							Name: "server",
+							VolumeMounts: []*api.VolumeMount{
+								{
+									Volume:      helper.StringToPtr("data"),
+									Destination: helper.StringToPtr("/srv/data"),
+									ReadOnly:    helper.BoolToPtr(true),
+								},
+								{
+									Volume:      helper.StringToPtr("certs"),
+									Destination: helper.StringToPtr("/etc/certs"),
+								},
+								{
+									Volume: helper.StringToPtr("logs"),
+								},
+							},
+						},
+						{
+							Name: "backup",
+							VolumeMounts: []*api.VolumeMount{
+								{
+									Volume:      helper.StringToPtr("data"),
+									Destination: helper.StringToPtr("/backup"),
+									ReadOnly:    helper.BoolToPtr(false),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	require.Equal([]interface{}{
+		map[string]interface{}{
+			"name":      "data",
+			"volume_id": "data-vol",
+			"read_only": true,
+			"mounts": []interface{}{
+				map[string]interface{}{
+					"task":        "server",
+					"destination": "/srv/data",
+					"read_only":   true,
+				},
+				map[string]interface{}{
+					"task":        "backup",
+					"destination": "/backup",
+					"read_only":   false,
+				},
+			},
+		},
+		map[string]interface{}{
+			"name":      "logs",
+			"volume_id": "logs-vol",
+			"read_only": false,
+			"mounts": []interface{}{
+				map[string]interface{}{
+					"task":        "server",
+					"destination": "",
+					"read_only":   false,
+				},
+			},
+		},
+	}, allocationCSIVolumesRaw(alloc))
+}
@@ -0,0 +1,107 @@
+package nomad
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func dataSourceCSIPlugins() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceCSIPluginsRead,
+
+		Schema: map[string]*schema.Schema{
+			"healthy_only": {
+				Description: "If true, only healthy plugins are returned.",
+				Optional:    true,
+				Type:        schema.TypeBool,
+			},
+
+			"plugins": {
+				Description: "CSI plugins registered in the cluster.",
+				Computed:    true,
+				Type:        schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"provider": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"healthy": {
+							Computed: true,
+							Type:     schema.TypeBool,
+						},
+						"controller_required": {
+							Computed: true,
+							Type:     schema.TypeBool,
+						},
+						"controllers_healthy": {
+							Computed: true,
+							Type:     schema.TypeInt,
+						},
+						"controllers_expected": {
+							Computed: true,
+							Type:     schema.TypeInt,
+						},
+						"nodes_healthy": {
+							Computed: true,
+							Type:     schema.TypeInt,
+						},
+						"nodes_expected": {
+							Computed: true,
+							Type:     schema.TypeInt,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceCSIPluginsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+
+	log.Printf("[DEBUG] Reading CSI plugins from Nomad")
+	resp, _, err := client.CSIPlugins().List(nil)
+	if err != nil {
+		return fmt.Errorf("error reading CSI plugins from Nomad: %s", err)
+	}
+
+	log.Printf("[DEBUG] Read CSI plugins from Nomad")
+	d.SetId(client.Address() + "/csi-plugins")
+
+	return d.Set("plugins", csiPluginsRaw(resp, d.Get("healthy_only").(bool)))
+}
+
+// csiPluginsRaw flattens the listed CSI plugins, skipping unhealthy ones if
+// healthyOnly is set.
+func csiPluginsRaw(resp []*api.CSIPluginListStub, healthyOnly bool) []interface{} {
+	plugins := make([]interface{}, 0, len(resp))
+	for _, p := range resp {
+		// A plugin is healthy if it has a healthy node and, when it needs
+		// one, a healthy controller.
+		healthy := p.NodesHealthy >= 1 && (!p.ControllerRequired || p.ControllersHealthy >= 1)
+		if healthyOnly && !healthy {
+			continue
+		}
+
+		plugins = append(plugins, map[string]interface{}{
+			"id":                   p.ID,
+			"provider":             p.Provider,
+			"healthy":              healthy,
+			"controller_required":  p.ControllerRequired,
+			"controllers_healthy":  p.ControllersHealthy,
+			"controllers_expected": p.ControllersExpected,
+			"nodes_healthy":        p.NodesHealthy,
+			"nodes_expected":       p.NodesExpected,
+		})
+	}
+
+	return plugins
+}
@@ -0,0 +1,122 @@
+package nomad
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataSourceCSIPlugins(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t); testCheckMinVersion(t, "0.11.0-beta1") },
+		Steps: []resource.TestStep{
+			{
+				Config: testDataSourceCSIPlugins_config,
+				Check: resource.ComposeTestCheckFunc(
+					testDataSourceCSIPlugins_check("data.nomad_csi_plugins.all"),
+					testDataSourceCSIPlugins_check("data.nomad_csi_plugins.healthy"),
+				),
+			},
+		},
+	})
+}
+
+var testDataSourceCSIPlugins_config = `
+
+data "nomad_csi_plugins" "all" {
+}
+
+data "nomad_csi_plugins" "healthy" {
+  healthy_only = true
+}
+
+`
+
+func testDataSourceCSIPlugins_check(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		resourceState := s.Modules[0].Resources[name]
+		if resourceState == nil {
+			return fmt.Errorf("resource %s not found in state %v", name, s.Modules[0].Resources)
+		}
+
+		iState := resourceState.Primary
+		if iState == nil {
+			return fmt.Errorf("resource has no primary instance")
+		}
+
+		// The test cluster isn't guaranteed to run any CSI plugins, so only
+		// check that the list was populated.
+		if _, err := strconv.ParseInt(iState.Attributes["plugins.#"], 10, 64); err != nil {
+			return fmt.Errorf("expected integer in state, got %s (%T)", iState.Attributes["plugins.#"], iState.Attributes["plugins.#"])
+		}
+
+		return nil
+	}
+}
+
+func TestCSIPluginsRaw(t *testing.T) {
+	require := require.New(t)
+
+	plugins := []*api.CSIPluginListStub{
+		{
+			ID:            "node-only",
+			Provider:      "hostpath",
+			NodesHealthy:  1,
+			NodesExpected: 1,
+		},
+		{
+			ID:                  "no-controller",
+			Provider:            "ebs",
+			ControllerRequired:  true,
+			ControllersHealthy:  0,
+			ControllersExpected: 1,
+			NodesHealthy:        2,
+			NodesExpected:       2,
+		},
+		{
+			ID:                  "no-nodes",
+			Provider:            "ebs",
+			ControllerRequired:  true,
+			ControllersHealthy:  1,
+			ControllersExpected: 1,
+			NodesHealthy:        0,
+			NodesExpected:       2,
+		},
+	}
+
+	healthy := func(raw []interface{}) map[string]bool {
+		m := make(map[string]bool)
+		for _, p := range raw {
+			p := p.(map[string]interface{})
+			m[p["id"].(string)] = p["healthy"].(bool)
+		}
+		return m
+	}
+
+	all := csiPluginsRaw(plugins, false)
+	require.Equal(map[string]bool{
+		"node-only":     true,
+		"no-controller": false,
+		"no-nodes":      false,
+	}, healthy(all))
+	require.Equal(map[string]interface{}{
+		"id":                   "no-controller",
+		"provider":             "ebs",
+		"healthy":              false,
+		"controller_required":  true,
+		"controllers_healthy":  0,
+		"controllers_expected": 1,
+		"nodes_healthy":        2,
+		"nodes_expected":       2,
+	}, all[1])
+
+	require.Equal(map[string]bool{
+		"node-only": true,
+	}, healthy(csiPluginsRaw(plugins, true)))
+}
@@ -23,7 +23,7 @@ func dataSourceJob() *schema.Resource {
 				Description: "Job Namespace",
 				Type:        schema.TypeString,
 				Optional:    true,
-				Default:     "default",
+				Computed:    true,
 			},
 			// computed attributes
 			"name": {
@@ -384,10 +384,7 @@ func dataSourceJobRead(d *schema.ResourceData, meta interface{}) error {
 	client := providerConfig.client
 
 	id := d.Get("job_id").(string)
-	ns := d.Get("namespace").(string)
-	if ns == "" {
-		ns = "default"
-	}
+	ns := resolveNamespace(d.Get("namespace").(string), providerConfig)
 	log.Printf("[DEBUG] Getting job status: %q/%q", ns, id)
 	job, _, err := client.Jobs().Info(id, &api.QueryOptions{
 		Namespace: ns,
@@ -12,6 +12,7 @@ import (
 type ProviderConfig struct {
 	client     *api.Client
 	vaultToken *string
+	namespace  string
 }
 
 func Provider() terraform.ResourceProvider {
@@ -30,6 +31,16 @@ func Provider() terraform.ResourceProvider {
 				DefaultFunc: schema.EnvDefaultFunc("NOMAD_REGION", ""),
 				Description: "Region of the target Nomad agent.",
 			},
+			"namespace": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Default namespace for resources that don't specify one.",
+			},
+			"no_default_namespace": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "If true, requests for resources without a namespace are sent without one rather than to the default namespace.",
+			},
 			"ca_file": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -65,12 +76,14 @@ func Provider() terraform.ResourceProvider {
 		ConfigureFunc: providerConfigure,
 
 		DataSourcesMap: map[string]*schema.Resource{
-			"nomad_acl_policy":  dataSourceAclPolicy(),
-			"nomad_acl_token":   dataSourceACLToken(),
-			"nomad_deployments": dataSourceDeployments(),
-			"nomad_job":         dataSourceJob(),
-			"nomad_namespaces":  dataSourceNamespaces(),
-			"nomad_regions":     dataSourceRegions(),
+			"nomad_acl_policy":             dataSourceAclPolicy(),
+			"nomad_acl_token":              dataSourceACLToken(),
+			"nomad_allocation_csi_volumes": dataSourceAllocationCSIVolumes(),
+			"nomad_csi_plugins":            dataSourceCSIPlugins(),
+			"nomad_deployments":            dataSourceDeployments(),
+			"nomad_job":                    dataSourceJob(),
+			"nomad_namespaces":             dataSourceNamespaces(),
+			"nomad_regions":                dataSourceRegions(),
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
@@ -84,6 +97,15 @@ func Provider() terraform.ResourceProvider {
 	}
 }
 
+// resolveNamespace returns the namespace a resource should target, falling
+// back to the provider's default namespace if ns is empty.
+func resolveNamespace(ns string, providerConfig ProviderConfig) string {
+	if ns == "" {
+		return providerConfig.namespace
+	}
+	return ns
+}
+
 // Get gets the value of the stored token, if any
 func getToken() (string, error) {
 	helper, err := config.DefaultTokenHelper()
@@ -117,6 +139,15 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		}
 	}
 
+	namespace := d.Get("namespace").(string)
+	if namespace == "" && !d.Get("no_default_namespace").(bool) {
+		namespace = api.DefaultNamespace
+	}
+
+	// DefaultConfig reads NOMAD_NAMESPACE, which would otherwise be used for
+	// any request we send without a namespace.
+	conf.Namespace = namespace
+
 	client, err := api.NewClient(conf)
 	if err != nil {
 		return nil, fmt.Errorf("failed to configure Nomad API: %s", err)
@@ -125,6 +156,7 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	res := ProviderConfig{
 		client:     client,
 		vaultToken: &vaultToken,
+		namespace:  namespace,
 	}
 
 	return res, nil
@@ -2,6 +2,8 @@ package nomad
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
@@ -38,6 +40,87 @@ func TestProvider_impl(t *testing.T) {
 	var _ terraform.ResourceProvider = Provider()
 }
 
+func TestProviderConfigure_namespace(t *testing.T) {
+	// NOMAD_NAMESPACE must not change where jobs without a namespace go.
+	if v, ok := os.LookupEnv("NOMAD_NAMESPACE"); ok {
+		defer os.Setenv("NOMAD_NAMESPACE", v)
+	} else {
+		defer os.Unsetenv("NOMAD_NAMESPACE")
+	}
+	os.Setenv("NOMAD_NAMESPACE", "from-env")
+
+	cases := []struct {
+		name     string
+		raw      map[string]interface{}
+		expected string
+	}{
+		{
+			name:     "default",
+			raw:      map[string]interface{}{},
+			expected: "default",
+		},
+		{
+			name:     "namespace",
+			raw:      map[string]interface{}{"namespace": "dev"},
+			expected: "dev",
+		},
+		{
+			name:     "no default namespace",
+			raw:      map[string]interface{}{"no_default_namespace": true},
+			expected: "",
+		},
+		{
+			name:     "namespace and no default namespace",
+			raw:      map[string]interface{}{"namespace": "dev", "no_default_namespace": true},
+			expected: "dev",
+		},
+	}
+
+	// Record the namespace the client sends when a request doesn't set one.
+	var gotNamespace string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		gotNamespace = req.URL.Query().Get("namespace")
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.raw["address"] = server.URL
+			c.raw["vault_token"] = "token"
+			d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, c.raw)
+
+			meta, err := providerConfigure(d)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if ns := meta.(ProviderConfig).namespace; ns != c.expected {
+				t.Fatalf("expected namespace %q, got %q", c.expected, ns)
+			}
+			if _, _, err := meta.(ProviderConfig).client.Jobs().List(nil); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if gotNamespace != c.expected {
+				t.Fatalf("expected client namespace %q, got %q", c.expected, gotNamespace)
+			}
+		})
+	}
+}
+
+func TestResolveNamespace(t *testing.T) {
+	providerConfig := ProviderConfig{namespace: "provider-ns"}
+
+	if ns := resolveNamespace("", providerConfig); ns != "provider-ns" {
+		t.Fatalf("expected provider namespace, got %q", ns)
+	}
+	if ns := resolveNamespace("job-ns", providerConfig); ns != "job-ns" {
+		t.Fatalf("expected resource namespace, got %q", ns)
+	}
+	if ns := resolveNamespace("", ProviderConfig{}); ns != "" {
+		t.Fatalf("expected empty namespace with no_default_namespace, got %q", ns)
+	}
+}
+
 var testProvider *schema.Provider
 var testProviders map[string]terraform.ResourceProvider
 
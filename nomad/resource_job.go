@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math/rand"
 	"reflect"
 	"sort"
 	"strconv"
@@ -13,7 +14,9 @@ import (
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 
+	"github.com/terraform-providers/terraform-provider-nomad/nomad/core/helper"
 	"github.com/terraform-providers/terraform-provider-nomad/nomad/core/jobspec"
 )
 
@@ -47,6 +50,12 @@ func resourceJob() *schema.Resource {
 				Type:        schema.TypeBool,
 			},
 
+			"purge_on_destroy": {
+				Description: "If true, the job will be purged from Nomad on destroy instead of only being stopped.",
+				Optional:    true,
+				Type:        schema.TypeBool,
+			},
+
 			"deregister_on_id_change": {
 				Description: "If true, the job will be deregistered when the job ID changes.",
 				Optional:    true,
@@ -61,6 +70,14 @@ func resourceJob() *schema.Resource {
 				Type:        schema.TypeBool,
 			},
 
+			"poll_interval": {
+				Description:  "If detach = false, the minimum number of seconds between deployment status checks, from 1 to 10.",
+				Optional:     true,
+				Default:      3,
+				Type:         schema.TypeInt,
+				ValidateFunc: validation.IntBetween(1, 10),
+			},
+
 			"deployment_id": {
 				Description: "If detach = false, the ID for the deployment associated with the last job create/update, if one exists.",
 				Computed:    true,
@@ -232,9 +249,14 @@ func resourceJobRegister(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
-	if job.Namespace == nil || *job.Namespace == "" {
-		defaultNamespace := "default"
-		job.Namespace = &defaultNamespace
+	if job.Namespace == nil {
+		job.Namespace = helper.StringToPtr("")
+	}
+	*job.Namespace = resolveNamespace(*job.Namespace, providerConfig)
+	if *job.Namespace == "" {
+		// With no_default_namespace, the plan keeps a job without a
+		// namespace where it was last read back, if anywhere.
+		*job.Namespace = d.Get("namespace").(string)
 	}
 
 	// Register the job
@@ -247,7 +269,9 @@ func resourceJobRegister(d *schema.ResourceData, meta interface{}) error {
 	resp, _, err := client.Jobs().RegisterOpts(job, &api.RegisterOptions{
 		PolicyOverride: d.Get("policy_override").(bool),
 		ModifyIndex:    wantModifyIndex,
-	}, nil)
+	}, &api.WriteOptions{
+		Namespace: *job.Namespace,
+	})
 	if err != nil {
 		return fmt.Errorf("error applying jobspec: %s", err)
 	}
@@ -264,13 +288,14 @@ func resourceJobRegister(d *schema.ResourceData, meta interface{}) error {
 		d.Set("deployment_status", "")
 	} else {
 		log.Printf("[DEBUG] will monitor deployment of job '%s'", *job.ID)
+		pollInterval := time.Duration(d.Get("poll_interval").(int)) * time.Second
 		stateConf := &resource.StateChangeConf{
 			Pending:    []string{"monitoring_deployment", "monitoring_evaluation"},
 			Target:     []string{"job_scheduled_without_deployment", "deployment_successful"},
-			Refresh:    deploymentStateRefreshFunc(client, evalId),
+			Refresh:    jitteredRefreshFunc(deploymentStateRefreshFunc(client, evalId, *job.Namespace), pollInterval),
 			Timeout:    timeout,
 			Delay:      10 * time.Second,
-			MinTimeout: 3 * time.Second,
+			MinTimeout: pollInterval,
 		}
 
 		state, err := stateConf.WaitForState()
@@ -295,8 +320,11 @@ func resourceJobRegister(d *schema.ResourceData, meta interface{}) error {
 }
 
 // deploymentStateRefreshFunc returns a resource.StateRefreshFunc that is used to watch
-// the deployment from a job create/update
-func deploymentStateRefreshFunc(client *api.Client, initialEvalId string) resource.StateRefreshFunc {
+// the deployment from a job create/update in the given namespace
+func deploymentStateRefreshFunc(client *api.Client, initialEvalId, namespace string) resource.StateRefreshFunc {
+	opts := &api.QueryOptions{
+		Namespace: namespace,
+	}
 
 	// evalId is the evaluation that we are currently monitoring. This will change
 	// along with follow-up evaluations.
@@ -310,7 +338,7 @@ func deploymentStateRefreshFunc(client *api.Client, initialEvalId string) resour
 		if deploymentId == "" {
 			// monitor the eval
 			log.Printf("[DEBUG] monitoring evaluation '%s'", evalId)
-			eval, _, err := client.Evaluations().Info(evalId, nil)
+			eval, _, err := client.Evaluations().Info(evalId, opts)
 			if err != nil {
 				log.Printf("[ERROR] error on Evaluation.Info during deploymentStateRefresh: %s", err)
 				return nil, "", err
@@ -341,7 +369,7 @@ func deploymentStateRefreshFunc(client *api.Client, initialEvalId string) resour
 		} else {
 			// monitor the deployment
 			var state string
-			deployment, _, err := client.Deployments().Info(deploymentId, nil)
+			deployment, _, err := client.Deployments().Info(deploymentId, opts)
 			if err != nil {
 				log.Printf("[ERROR] error on Deployment.Info during deploymentStateRefresh: %s", err)
 				return nil, "", err
@@ -364,6 +392,21 @@ func deploymentStateRefreshFunc(client *api.Client, initialEvalId string) resour
 	}
 }
 
+// deploymentPollJitter returns a random delay of up to half the poll interval,
+// so that jobs applied together don't all poll Nomad in lockstep.
+func deploymentPollJitter(pollInterval time.Duration) time.Duration {
+	return time.Duration(rand.Int63n(int64(pollInterval/2) + 1))
+}
+
+// jitteredRefreshFunc delays every call to refresh by deploymentPollJitter,
+// since StateChangeConf otherwise polls on a fixed backoff.
+func jitteredRefreshFunc(refresh resource.StateRefreshFunc, pollInterval time.Duration) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		time.Sleep(deploymentPollJitter(pollInterval))
+		return refresh()
+	}
+}
+
 func resourceJobDeregister(d *schema.ResourceData, meta interface{}) error {
 	providerConfig := meta.(ProviderConfig)
 	client := providerConfig.client
@@ -381,12 +424,9 @@ func resourceJobDeregister(d *schema.ResourceData, meta interface{}) error {
 	id := d.Id()
 	log.Printf("[DEBUG] deregistering job: %q", id)
 	opts := &api.WriteOptions{
-		Namespace: d.Get("namespace").(string),
+		Namespace: resolveNamespace(d.Get("namespace").(string), providerConfig),
 	}
-	if opts.Namespace == "" {
-		opts.Namespace = "default"
-	}
-	_, _, err := client.Jobs().Deregister(id, false, opts)
+	_, _, err := client.Jobs().Deregister(id, d.Get("purge_on_destroy").(bool), opts)
 	if err != nil {
 		return fmt.Errorf("error deregistering job: %s", err)
 	}
@@ -400,10 +440,7 @@ func resourceJobRead(d *schema.ResourceData, meta interface{}) error {
 
 	id := d.Id()
 	opts := &api.QueryOptions{
-		Namespace: d.Get("namespace").(string),
-	}
-	if opts.Namespace == "" {
-		opts.Namespace = "default"
+		Namespace: resolveNamespace(d.Get("namespace").(string), providerConfig),
 	}
 	log.Printf("[DEBUG] reading information for job %q in namespace %q", id, opts.Namespace)
 	job, _, err := client.Jobs().Info(id, opts)
@@ -420,7 +457,7 @@ func resourceJobRead(d *schema.ResourceData, meta interface{}) error {
 	}
 	log.Printf("[DEBUG] found job %q in namespace %q", *job.Name, *job.Namespace)
 
-	allocStubs, _, err := client.Jobs().Allocations(id, false, nil)
+	allocStubs, _, err := client.Jobs().Allocations(id, false, opts)
 	if err != nil {
 		log.Printf("[WARN] error listing allocations for Job %q, will return empty list", id)
 	}
@@ -477,15 +514,17 @@ func resourceJobCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
 		return err
 	}
 
-	defaultNamespace := "default"
-	if job.Namespace == nil || *job.Namespace == "" {
-		job.Namespace = &defaultNamespace
+	if job.Namespace == nil {
+		job.Namespace = helper.StringToPtr("")
 	}
+	*job.Namespace = resolveNamespace(*job.Namespace, providerConfig)
 
 	resp, _, err := client.Jobs().PlanOpts(job, &api.PlanOptions{
 		Diff:           false,
 		PolicyOverride: d.Get("policy_override").(bool),
-	}, nil)
+	}, &api.WriteOptions{
+		Namespace: *job.Namespace,
+	})
 	if err != nil {
 		log.Printf("[WARN] failed to validate Nomad plan: %s", err)
 	}
@@ -502,7 +541,26 @@ func resourceJobCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
 
 	// If the identity has changed and the config asks us to deregister on identity
 	// change then the id field "forces new resource".
-	if d.Get("namespace").(string) != *job.Namespace {
+	oldNamespace := d.Get("namespace").(string)
+	if *job.Namespace == "" && oldNamespace != "" {
+		// With no_default_namespace, a jobspec without a namespace stays in
+		// the namespace we last read back, unless it has just dropped an
+		// explicit namespace and so may be moving.
+		oldSpecJSON, _ := d.GetChange("json")
+		if !jobspecHasNamespace(oldSpecRaw.(string), oldSpecJSON.(bool)) {
+			job.Namespace = &oldNamespace
+		}
+	}
+
+	if *job.Namespace == "" {
+		// We won't know where Nomad places the job until after registration,
+		// so a job leaving a known namespace must be replaced.
+		d.SetNewComputed("namespace")
+		if oldNamespace != "" {
+			log.Printf("[DEBUG] unresolved namespace forces new resource")
+			d.ForceNew("namespace")
+		}
+	} else if oldNamespace != *job.Namespace {
 		log.Printf("[DEBUG] namespace change forces new resource")
 		d.SetNew("namespace", job.Namespace)
 		d.ForceNew("namespace")
@@ -546,6 +604,13 @@ func resourceJobCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
 	return nil
 }
 
+// jobspecHasNamespace returns true if raw parses to a job that sets its own
+// namespace.
+func jobspecHasNamespace(raw string, is_json bool) bool {
+	job, err := parseJobspec(raw, is_json, nil)
+	return err == nil && job.Namespace != nil && *job.Namespace != ""
+}
+
 func parseJobspec(raw string, is_json bool, vaultToken *string) (*api.Job, error) {
 	var job *api.Job
 	var err error
@@ -4,10 +4,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -19,6 +22,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 
 	r "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
@@ -56,6 +60,24 @@ func TestResourceJob_namespace(t *testing.T) {
 	})
 }
 
+func TestResourceJob_providerNamespace(t *testing.T) {
+	r.Test(t, r.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t); testCheckEnt(t) },
+		Steps: []r.TestStep{
+			{
+				Config: testResourceJob_providerNamespaceConfig,
+				Check: r.ComposeTestCheckFunc(
+					testResourceJob_initialCheckNS(t, "jobresource-provider-namespace"),
+					testResourceJob_hasAllocations,
+				),
+			},
+		},
+
+		CheckDestroy: testResourceJob_checkDestroyNS("foo", "jobresource-provider-namespace"),
+	})
+}
+
 func TestResourceJob_v086(t *testing.T) {
 	r.Test(t, r.TestCase{
 		Providers: testProviders,
@@ -264,6 +286,32 @@ func TestResourceJob_disableDestroyDeregister(t *testing.T) {
 	})
 }
 
+func TestResourceJob_purgeOnDestroy(t *testing.T) {
+	r.Test(t, r.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []r.TestStep{
+			{
+				Config: testResourceJob_purgeOnDestroy,
+				Check:  testResourceJob_initialCheck(t),
+			},
+		},
+
+		CheckDestroy: func(*terraform.State) error {
+			providerConfig := testProvider.Meta().(ProviderConfig)
+			client := providerConfig.client
+			_, _, err := client.Jobs().Info("foo", nil)
+			if err == nil {
+				return fmt.Errorf("job was not purged")
+			}
+			if !strings.Contains(err.Error(), "404") {
+				return err
+			}
+			return nil
+		},
+	})
+}
+
 func TestResourceJob_rename(t *testing.T) {
 	r.Test(t, r.TestCase{
 		Providers: testProviders,
@@ -463,6 +511,48 @@ resource "nomad_job" "test" {
 }
 `
 
+var testResourceJob_providerNamespaceConfig = `
+provider "nomad" {
+  namespace = "jobresource-provider-namespace"
+}
+
+resource "nomad_namespace" "provider-namespace" {
+  name = "jobresource-provider-namespace"
+}
+
+resource "nomad_job" "test" {
+	depends_on = [nomad_namespace.provider-namespace]
+
+	detach = false
+
+	jobspec = <<EOT
+		job "foo" {
+			datacenters = ["dc1"]
+			type = "batch"
+			group "foo" {
+				task "foo" {
+					driver = "raw_exec"
+					config {
+						command = "/bin/sleep"
+						args = ["10"]
+					}
+
+					resources {
+						cpu = 100
+						memory = 10
+					}
+
+					logs {
+						max_files = 3
+						max_file_size = 10
+					}
+				}
+			}
+		}
+	EOT
+}
+`
+
 var testResourceJob_changeNamespaceConfig = `
 resource "nomad_namespace" "test-namespace" {
   name = "jobresource-test-namespace"
@@ -628,6 +718,37 @@ resource "nomad_job" "test" {
 }
 `
 
+var testResourceJob_purgeOnDestroy = `
+resource "nomad_job" "test" {
+    purge_on_destroy = true
+    jobspec = <<EOT
+		job "foo" {
+			datacenters = ["dc1"]
+			type = "service"
+			group "foo" {
+				task "foo" {
+					driver = "raw_exec"
+					config {
+						command = "/bin/sleep"
+						args = ["30"]
+					}
+
+					resources {
+						cpu = 100
+						memory = 10
+					}
+
+					logs {
+						max_files = 3
+						max_file_size = 10
+					}
+				}
+			}
+		}
+	EOT
+}
+`
+
 var testResourceJob_noDestroy = `
 resource "nomad_job" "test" {
     deregister_on_destroy = false
@@ -699,7 +820,9 @@ func testResourceJob_initialCheckNS(t *testing.T, expectedNamespace string) r.Te
 			return fmt.Errorf("job namespace is %q; want %q", got, want)
 		}
 
-		wantAllocs, _, err := client.Jobs().Allocations(jobID, false, nil)
+		wantAllocs, _, err := client.Jobs().Allocations(jobID, false, &api.QueryOptions{
+			Namespace: expectedNamespace,
+		})
 		if err != nil {
 			return fmt.Errorf("error reading back job: %s", err)
 		}
@@ -721,6 +844,24 @@ func testResourceJob_initialCheckNS(t *testing.T, expectedNamespace string) r.Te
 	}
 }
 
+func testResourceJob_hasAllocations(s *terraform.State) error {
+	resourceState := s.Modules[0].Resources["nomad_job.test"]
+	if resourceState == nil {
+		return errors.New("resource not found in state")
+	}
+
+	instanceState := resourceState.Primary
+	if instanceState == nil {
+		return errors.New("resource has no primary instance")
+	}
+
+	if n, _ := strconv.Atoi(instanceState.Attributes["allocation_ids.#"]); n == 0 {
+		return errors.New("job 'allocation_ids' is empty")
+	}
+
+	return nil
+}
+
 func testResourceJob_v086Check(s *terraform.State) error {
 
 	resourceState := s.Modules[0].Resources["nomad_job.test"]
@@ -1342,6 +1483,239 @@ func TestResourceJob_serverNotAvailableForPlan(t *testing.T) {
 	})
 }
 
+func TestDeploymentPollJitter(t *testing.T) {
+	pollInterval := 4 * time.Second
+	for i := 0; i < 100; i++ {
+		jitter := deploymentPollJitter(pollInterval)
+		require.True(t, jitter >= 0, "jitter %s is negative", jitter)
+		require.True(t, jitter <= pollInterval/2, "jitter %s exceeds half of %s", jitter, pollInterval)
+	}
+}
+
+func TestJitteredRefreshFunc(t *testing.T) {
+	calls := 0
+	refresh := jitteredRefreshFunc(func() (interface{}, string, error) {
+		calls++
+		return "result", "deployment_successful", nil
+	}, 20*time.Millisecond)
+
+	for i := 0; i < 5; i++ {
+		result, state, err := refresh()
+		require.NoError(t, err)
+		require.Equal(t, "result", result)
+		require.Equal(t, "deployment_successful", state)
+	}
+	require.Equal(t, 5, calls)
+}
+
+// testResourceJob_fakeNomad starts an HTTP server standing in for the Nomad
+// API, which records the namespace each request was made against.
+func testResourceJob_fakeNomad(t *testing.T, job *api.Job) (*httptest.Server, func(string) (string, bool)) {
+	var lock sync.Mutex
+	namespaces := make(map[string]string)
+	namespaceFor := func(call string) (string, bool) {
+		lock.Lock()
+		defer lock.Unlock()
+		ns, ok := namespaces[call]
+		return ns, ok
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		lock.Lock()
+		namespaces[req.Method+" "+req.URL.Path] = req.URL.Query().Get("namespace")
+		lock.Unlock()
+
+		var resp interface{}
+		switch req.Method + " " + req.URL.Path {
+		case "PUT /v1/jobs":
+			resp = api.JobRegisterResponse{EvalID: "eval1", JobModifyIndex: 10}
+		case "PUT /v1/job/foo/plan":
+			resp = api.JobPlanResponse{JobModifyIndex: 10}
+		case "GET /v1/job/foo":
+			resp = job
+		case "GET /v1/job/foo/allocations":
+			resp = []*api.AllocationListStub{{ID: "alloc1"}}
+		case "DELETE /v1/job/foo":
+			resp = api.JobDeregisterResponse{EvalID: "eval2"}
+		case "GET /v1/evaluation/eval1":
+			resp = api.Evaluation{ID: "eval1", Status: "complete", DeploymentID: "deploy1"}
+		case "GET /v1/deployment/deploy1":
+			resp = api.Deployment{ID: "deploy1", Status: "successful"}
+		default:
+			t.Errorf("unexpected request: %s %s", req.Method, req.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+
+	return server, namespaceFor
+}
+
+func TestResourceJob_namespaceTargets(t *testing.T) {
+	job := &api.Job{
+		ID:             helper.StringToPtr("foo"),
+		Name:           helper.StringToPtr("foo"),
+		Namespace:      helper.StringToPtr("provider-ns"),
+		Type:           helper.StringToPtr("batch"),
+		JobModifyIndex: uint64ToPtr(10),
+	}
+	server, namespaceFor := testResourceJob_fakeNomad(t, job)
+	defer server.Close()
+
+	client, err := api.NewClient(&api.Config{Address: server.URL})
+	require.NoError(t, err)
+	vaultToken := ""
+	meta := ProviderConfig{
+		client:     client,
+		vaultToken: &vaultToken,
+		namespace:  "provider-ns",
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceJob().Schema, map[string]interface{}{
+		"jobspec": `
+job "foo" {
+	datacenters = ["dc1"]
+	type = "batch"
+	group "foo" {
+		task "foo" {
+			driver = "raw_exec"
+		}
+	}
+}`,
+	})
+
+	require.NoError(t, resourceJobRegister(d, meta))
+	require.Equal(t, "provider-ns", d.Get("namespace"))
+	require.Equal(t, []interface{}{"alloc1"}, d.Get("allocation_ids"))
+	require.NoError(t, resourceJobDeregister(d, meta))
+
+	refresh := deploymentStateRefreshFunc(client, "eval1", "provider-ns")
+	for i := 0; i < 2; i++ {
+		_, _, err := refresh()
+		require.NoError(t, err)
+	}
+
+	for _, call := range []string{
+		"PUT /v1/jobs",
+		"GET /v1/job/foo",
+		"GET /v1/job/foo/allocations",
+		"DELETE /v1/job/foo",
+		"GET /v1/evaluation/eval1",
+		"GET /v1/deployment/deploy1",
+	} {
+		ns, ok := namespaceFor(call)
+		require.True(t, ok, "expected a %s request", call)
+		require.Equal(t, "provider-ns", ns, "namespace for %s", call)
+	}
+}
+
+func TestResourceJob_unresolvedNamespaceDiff(t *testing.T) {
+	job := &api.Job{
+		ID:   helper.StringToPtr("foo"),
+		Name: helper.StringToPtr("foo"),
+	}
+	server, _ := testResourceJob_fakeNomad(t, job)
+	defer server.Close()
+
+	client, err := api.NewClient(&api.Config{Address: server.URL})
+	require.NoError(t, err)
+	vaultToken := ""
+	meta := ProviderConfig{
+		client:     client,
+		vaultToken: &vaultToken,
+	}
+
+	jobspec := `
+job "foo" {
+	datacenters = ["dc1"]
+	type = "batch"
+	group "foo" {
+		task "foo" {
+			driver = "raw_exec"
+		}
+	}
+}`
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"jobspec": jobspec,
+	})
+
+	// A new job without a namespace doesn't know where Nomad will place it.
+	diff, err := resourceJob().Diff(nil, config, meta)
+	require.NoError(t, err)
+	require.True(t, diff.Attributes["namespace"].NewComputed)
+
+	// A job dropping its explicit namespace must be replaced.
+	state := &terraform.InstanceState{
+		ID: "foo",
+		Attributes: map[string]string{
+			"jobspec":      strings.Replace(jobspec, `job "foo" {`, `job "foo" {`+"\n\tnamespace = \"dev\"", 1),
+			"name":         "foo",
+			"namespace":    "dev",
+			"modify_index": "10",
+		},
+	}
+	diff, err = resourceJob().Diff(state, config, meta)
+	require.NoError(t, err)
+	require.True(t, diff.Attributes["namespace"].NewComputed)
+	require.True(t, diff.Attributes["namespace"].RequiresNew)
+	require.True(t, diff.RequiresNew())
+}
+
+func TestResourceJob_unresolvedNamespaceInPlace(t *testing.T) {
+	job := &api.Job{
+		ID:   helper.StringToPtr("foo"),
+		Name: helper.StringToPtr("foo"),
+	}
+	server, _ := testResourceJob_fakeNomad(t, job)
+	defer server.Close()
+
+	client, err := api.NewClient(&api.Config{Address: server.URL})
+	require.NoError(t, err)
+	vaultToken := ""
+	meta := ProviderConfig{
+		client:     client,
+		vaultToken: &vaultToken,
+	}
+
+	jobspec := `
+job "foo" {
+	datacenters = ["%s"]
+	type = "batch"
+	group "foo" {
+		task "foo" {
+			driver = "raw_exec"
+		}
+	}
+}`
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"jobspec": fmt.Sprintf(jobspec, "dc2"),
+	})
+
+	// The namespace in state came from Nomad, not the jobspec, so editing
+	// the jobspec updates the job where it is.
+	state := &terraform.InstanceState{
+		ID: "foo",
+		Attributes: map[string]string{
+			"jobspec":      fmt.Sprintf(jobspec, "dc1"),
+			"name":         "foo",
+			"namespace":    "default",
+			"modify_index": "10",
+		},
+	}
+	diff, err := resourceJob().Diff(state, config, meta)
+	require.NoError(t, err)
+	require.False(t, diff.RequiresNew())
+	if nsDiff, ok := diff.Attributes["namespace"]; ok {
+		require.False(t, nsDiff.NewComputed)
+		require.Equal(t, "default", nsDiff.New)
+	}
+}
+
+func uint64ToPtr(u uint64) *uint64 {
+	return &u
+}
+
 func TestVolumeSorting(t *testing.T) {
 	require := require.New(t)
 